@@ -2,215 +2,131 @@ package main
 
 import (
 	"encoding/xml"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"os"
 )
 
-type Launchable struct {
-	Type     string `xml:"type,attr"`
-	Contents string `xml:",chardata"`
-}
-
-type Component struct {
-	XMLName         xml.Name     `xml:"component"`
-	Type            string       `xml:"type,attr"`
-	ID              string       `xml:"id"`
-	Name            string       `xml:"name"`
-	Summary         string       `xml:"summary"`
-	MetadataLicense string       `xml:"metadata_license"`
-	ProjectLicense  string       `xml:"project_license"`
-	Description     string       `xml:"description"`
-	Launchable      Launchable   `xml:"launchable"`
-	Screenshots     []Screenshot `xml:"screenshots>screenshot"`
-}
-
-type Screenshot struct {
-	Caption     string `xml:"caption"`
-	Image       Image  `xml:"image"`
-	Environment string `xml:"environment,attr"`
-}
-
-type Image struct {
-	Type   string `xml:"type,attr"`
-	Width  int    `xml:"width,attr"`
-	Height int    `xml:"height,attr"`
-	Source string `xml:",chardata"`
-}
-
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: appstreamlint <file>")
-		os.Exit(1)
-	}
-	filePath := os.Args[1]
-	xmlFile, err := os.Open(filePath)
-	if err != nil {
-		fmt.Println("Error opening file:", err)
-		os.Exit(1)
+	if len(os.Args) > 1 && os.Args[1] == "catalog" {
+		if err := runCatalog(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		return
 	}
-	defer xmlFile.Close()
+	runLint(os.Args[1:])
+}
 
-	byteValue, err := ioutil.ReadAll(xmlFile)
-	if err != nil {
-		fmt.Println("Error reading file:", err)
-		os.Exit(1)
+// runLint implements appstreamlint's default mode: validate a single
+// metainfo file and print a report.
+func runLint(args []string) {
+	fs := flag.NewFlagSet("appstreamlint", flag.ExitOnError)
+	format := fs.String("format", "text", "report format: text, json, yaml or sarif")
+	failOn := fs.String("fail-on", "error", "minimum severity that causes a non-zero exit: error, warning, info or pedantic")
+	desktopDir := fs.String("desktop-dir", "", "directory to search for the .desktop file named by a desktop-id launchable (default: the metainfo file's directory)")
+	iconsDir := fs.String("icons-dir", "", "directory to search for a hicolor-themed icon (default: the metainfo file's directory)")
+	strictID := fs.Bool("strict-id", false, "validate <id/> as a reverse-DNS string and check its coherence with the desktop-id launchable and filename")
+	fetchScreenshotsFlag := fs.Bool("fetch-screenshots", false, "fetch each screenshot image over the network and verify its declared dimensions")
+	fetchTimeout := fs.Duration("fetch-timeout", defaultFetchTimeout, "timeout for each screenshot fetch, used with --fetch-screenshots")
+	fetchUserAgent := fs.String("fetch-user-agent", defaultFetchUserAgent, "User-Agent header sent when fetching screenshots, used with --fetch-screenshots")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: appstreamlint [--format=text|json|yaml|sarif] [--fail-on=error|warning|info|pedantic] [--desktop-dir=dir] [--icons-dir=dir] [--strict-id] [--fetch-screenshots] <file>")
+		fs.PrintDefaults()
 	}
+	fs.Parse(args)
 
-	var component Component
-	if err := xml.Unmarshal(byteValue, &component); err != nil {
-		fmt.Println("Error parsing XML:", err)
+	if fs.NArg() != 1 {
+		fs.Usage()
 		os.Exit(1)
 	}
+	filePath := fs.Arg(0)
 
-	// Check filename
-	// While desktop-application metadata is commonly stored in /usr/share/metainfo/%{id}.metainfo.xml (with a .metainfo.xml extension),
-	// using a .appdata.xml extension is also permitted for this component type for legacy compatibility.
-	// NOTE: This implementation will accept both .metainfo.xml and .appdata.xml extensions because the AppStream format should always be backwards compatible.
-	if filePath != component.ID+".appdata.xml" && filePath != component.ID+".metainfo.xml" {
-		fmt.Println("Error: Filename must be the same as the ID with .appdata.xml extension")
-		// Print the correct filename
-		fmt.Println("Correct filename:", component.ID+".metainfo.xml")
-		// Print the actual filename
-		fmt.Println("Actual filename:", filePath)
+	failOnSeverity := Severity(*failOn)
+	switch failOnSeverity {
+	case SeverityError, SeverityWarning, SeverityInfo, SeverityPedantic:
+	default:
+		fmt.Fprintln(os.Stderr, "Error: --fail-on must be one of error, warning, info, pedantic")
 		os.Exit(1)
 	}
 
-	// Check required fields
-	requiredFields := map[string]string{
-		"Type":            component.Type,
-		"ID":              component.ID,
-		"Name":            component.Name,
-		"Summary":         component.Summary,
-		"MetadataLicense": component.MetadataLicense,
-		"ProjectLicense":  component.ProjectLicense,
-		"Description":     component.Description,
-		"LaunchableType":  component.Launchable.Type,
-		"Launchable":      component.Launchable.Contents,
+	opts := ValidateOptions{
+		DesktopDir: *desktopDir,
+		IconsDir:   *iconsDir,
+		StrictID:   *strictID,
+		Fetch: FetchOptions{
+			Enabled:   *fetchScreenshotsFlag,
+			Timeout:   *fetchTimeout,
+			UserAgent: *fetchUserAgent,
+		},
 	}
-
-	for field, value := range requiredFields {
-		if value == "" {
-			fmt.Printf("Error: %s must not be empty\n", field)
-			os.Exit(1)
-		}
+	report, err := lintFile(filePath, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
 	}
 
-	// The desktop-application component type is the same as the desktop component type -
-	// desktop is the older type identifier for desktop-applications and should not be used for new metainfo files,
-	// unless compatibility with very old AppStream tools (pre 2016) is still wanted.
-	// NOTE: Both types will be accepted in this implementation, because the AppStream format should always be backwards compatible.
-	if component.Type != "desktop-application" && component.Type != "desktop" {
-		fmt.Println("Error: Type must be 'desktop-application'")
+	if err := printReport(report, *format); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
 	}
 
-	// For desktop applications, the <id/> tag value must follow the reverse-DNS scheme
-	// (e.g. org.gnome.gedit, org.kde.dolphin, etc.) and must not contain any spaces or special characters.
-	// NOTE: Reverse-DNS is not enforced in this implementation as we think it complicates things, especially for new developers without a domain.
-	// Furthermore, the <id/> tag value used to contain the name of the desktop file with the .desktop extension, unforunately, this has changed over time
-	// but the <id/> tag value should not change once it has been set for a given application.
-
-	// https://www.freedesktop.org/software/appstream/docs/chap-Metadata.html#tag-metadata_license
-	// NOTE: The AppStream specification might allow more licenses than the ones listed below in the future. So this implementation will only inform the user
-	// if the metadata license is not allowed. The user can then check the latest AppStream specification for the allowed licenses.
-	allowedLicenses := []string{
-		"FSFAP",
-		"MIT",
-		"0BSD",
-		"CC0-1.0",
-		"CC-BY-3.0",
-		"CC-BY-4.0",
-		"CC-BY-SA-3.0",
-		"CC-BY-SA-4.0",
-		"GFDL-1.1",
-		"GFDL-1.2",
-		"GFDL-1.3",
-		"BSL-1.0",
-		"FTL",
-		"FSFUL",
+	if report.hasAtLeast(failOnSeverity) {
+		os.Exit(1)
 	}
+}
 
-	allowed := false
-	for _, license := range allowedLicenses {
-		if component.MetadataLicense == license {
-			allowed = true
-			break
-		}
-	}
-	if !allowed {
-		fmt.Println("Warning: Metadata license is not allowed")
-		fmt.Println("Allowed licenses:", allowedLicenses)
-		fmt.Println("Actual license:", component.MetadataLicense)
+// lintFile parses filePath as a metainfo file and runs the Validator
+// registered for its component type, collecting every Hint found.
+func lintFile(filePath string, opts ValidateOptions) (Report, error) {
+	xmlFile, err := os.Open(filePath)
+	if err != nil {
+		return Report{}, fmt.Errorf("opening file: %w", err)
 	}
+	defer xmlFile.Close()
 
-	// The human-readable name of the application. This is the name you want users to see prior to installing the application.
-	// Check that it is at least 2 characters long.
-	if len(component.Name) < 2 {
-		fmt.Println("Error: Name must be at least 2 characters long")
-		os.Exit(1)
+	byteValue, err := ioutil.ReadAll(xmlFile)
+	if err != nil {
+		return Report{}, fmt.Errorf("reading file: %w", err)
 	}
 
-	// A short summary on what this application does, roughly equivalent to the Comment field of the accompanying .desktop file of the application.
-	// Check that it is at least 10 characters long.
-	if len(component.Summary) < 10 {
-		fmt.Println("Error: Summary must be at least 10 characters long")
-		os.Exit(1)
+	var component Component
+	if err := xml.Unmarshal(byteValue, &component); err != nil {
+		return Report{}, fmt.Errorf("parsing XML: %w", err)
 	}
 
-	// The <launchable/> tag has a required type property indicating the system that is used to launch the component. The following types are allowed:
-	// desktop-id: The component is launched using a desktop file. The desktop file is identified by the <id/> tag value.
-	// NOTE: This implementation will only accept the desktop-id type.
-	// <launchable type="desktop-id">myapplication.desktop</launchable>
-	// So check the type attribute and the value.
-	if component.Launchable.Type != "desktop-id" {
-		fmt.Println("Error: Launchable type must be 'desktop-id'")
-		fmt.Println("Actual launchable type:", component.Launchable.Type)
-		os.Exit(1)
+	validator, ok := validators[component.Type]
+	if !ok {
+		return Report{File: filePath, Hints: []Hint{
+			errorHint("metainfo-unsupported-component-type", fmt.Sprintf("unsupported component type %q", component.Type)),
+		}}, nil
 	}
 
-	// https://www.freedesktop.org/software/appstream/docs/chap-Metadata.html#tag-screenshots
+	return Report{File: filePath, Hints: validator.Validate(filePath, component, opts)}, nil
+}
 
-	if len(component.Screenshots) == 0 {
-		fmt.Println("Warning: No screenshots tag found")
-	} else {
-		if len(component.Screenshots) == 0 {
-			fmt.Println("Error: No screenshot tag found inside screenshots tag")
-			os.Exit(1)
+// printReport writes report to stdout in the requested format.
+func printReport(report Report, format string) error {
+	switch format {
+	case "text":
+		fmt.Print(formatText(report))
+	case "json":
+		out, err := formatJSON(report)
+		if err != nil {
+			return err
 		}
-
-		for _, screenshot := range component.Screenshots {
-			if screenshot.Image.Type != "source" && screenshot.Image.Type != "video" && screenshot.Image.Type != "" {
-				fmt.Println("Error: Image type must be 'source' or 'video'")
-				os.Exit(1)
-			}
-			if screenshot.Image.Type == "source" {
-
-				// The image source must be a valid URL, starting with http:// or https:// and following RFC 3986.
-				// NOTE: For simplicity, this implementation will only check if the source starts with http:// or https:// and ends with a valid image extension.
-				if len(screenshot.Image.Source) < 7 || (screenshot.Image.Source[:7] != "http://" && screenshot.Image.Source[:8] != "https://") {
-					fmt.Println("Error: Image source must start with http:// or https://")
-					os.Exit(1)
-				}
-				// Check if the source ends with a valid image extension
-				validExtensions := []string{".png", ".jpg", ".jpeg"} // NOTE: It is debatable whether other image extensions should be allowed
-				valid := false
-				for _, ext := range validExtensions {
-					if len(screenshot.Image.Source) > len(ext) && screenshot.Image.Source[len(screenshot.Image.Source)-len(ext):] == ext {
-						valid = true
-						break
-					}
-				}
-				if !valid {
-					fmt.Println("Error: Image source must end with a valid image extension")
-					fmt.Println("Valid extensions:", validExtensions)
-					fmt.Println("Actual extension:", screenshot.Image.Source[len(screenshot.Image.Source)-4:])
-					os.Exit(1)
-				}
-			}
+		fmt.Print(out)
+	case "yaml":
+		fmt.Print(formatYAML(report))
+	case "sarif":
+		out, err := formatSARIF(report)
+		if err != nil {
+			return err
 		}
-
+		fmt.Print(out)
+	default:
+		return fmt.Errorf("unknown --format %q, want text, json, yaml or sarif", format)
 	}
-
-	fmt.Println("Validation complete.")
+	return nil
 }
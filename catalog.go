@@ -0,0 +1,176 @@
+package main
+
+import (
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// catalogSchemaVersion is the AppStream Catalog Metadata format version
+// this tool emits.
+const catalogSchemaVersion = "0.16"
+
+// CatalogOptions configures the "catalog" subcommand.
+type CatalogOptions struct {
+	Origin       string
+	MediaBaseURL string
+	Architecture string
+}
+
+// runCatalog implements the "catalog" subcommand: walk a directory of
+// metainfo files, validate each, and emit a merged AppStream Catalog XML
+// document wrapping every component that validated without errors.
+func runCatalog(args []string) error {
+	fs := flag.NewFlagSet("appstreamlint catalog", flag.ExitOnError)
+	origin := fs.String("origin", "", "origin attribute of the generated <components/> catalog, e.g. the repository name")
+	mediaBaseURL := fs.String("media-baseurl", "", "media_baseurl attribute of the generated <components/> catalog")
+	architecture := fs.String("architecture", "", "architecture attribute of the generated <components/> catalog")
+	gzipOutput := fs.Bool("gzip", false, "gzip-compress the catalog, matching the myrepo-mydistro.xml.gz naming convention")
+	output := fs.String("output", "", "file to write the catalog to (default: stdout)")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: appstreamlint catalog [--origin=name] [--media-baseurl=url] [--architecture=arch] [--gzip] [--output=file] <dir>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	dir := fs.Arg(0)
+
+	catOpts := CatalogOptions{Origin: *origin, MediaBaseURL: *mediaBaseURL, Architecture: *architecture}
+	catalog, skipped, err := buildCatalog(dir, ValidateOptions{}, catOpts)
+	if err != nil {
+		return err
+	}
+	for _, s := range skipped {
+		fmt.Fprintln(os.Stderr, "Skipping:", s)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", *output, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if !*gzipOutput {
+		_, err := out.WriteString(catalog)
+		return err
+	}
+
+	gz := gzip.NewWriter(out)
+	if _, err := gz.Write([]byte(catalog)); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// buildCatalog walks dir for *.metainfo.xml / *.appdata.xml files,
+// validates each with lintFile, and returns the merged AppStream Catalog
+// XML document along with a description of every component skipped
+// because it failed to parse or produced an error-severity hint.
+func buildCatalog(dir string, valOpts ValidateOptions, catOpts CatalogOptions) (catalog string, skipped []string, err error) {
+	files, err := findMetainfoFiles(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var buf strings.Builder
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	buf.WriteString("<components")
+	fmt.Fprintf(&buf, " version=%q", catalogSchemaVersion)
+	if catOpts.Origin != "" {
+		fmt.Fprintf(&buf, " origin=%q", catOpts.Origin)
+	}
+	if catOpts.MediaBaseURL != "" {
+		fmt.Fprintf(&buf, " media_baseurl=%q", catOpts.MediaBaseURL)
+	}
+	if catOpts.Architecture != "" {
+		fmt.Fprintf(&buf, " architecture=%q", catOpts.Architecture)
+	}
+	buf.WriteString(">\n")
+
+	for _, file := range files {
+		report, lintErr := lintFile(file, valOpts)
+		if lintErr != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %s", file, lintErr))
+			continue
+		}
+		if report.hasAtLeast(SeverityError) {
+			skipped = append(skipped, fmt.Sprintf("%s: has error-severity hints", file))
+			continue
+		}
+
+		data, readErr := ioutil.ReadFile(file)
+		if readErr != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %s", file, readErr))
+			continue
+		}
+		buf.WriteString(indentXML(extractComponentXML(data), "  "))
+		buf.WriteString("\n")
+	}
+
+	buf.WriteString("</components>\n")
+	return buf.String(), skipped, nil
+}
+
+// findMetainfoFiles returns every *.metainfo.xml / *.appdata.xml file
+// under dir, sorted for reproducible catalog output.
+func findMetainfoFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if strings.HasSuffix(path, ".metainfo.xml") || strings.HasSuffix(path, ".appdata.xml") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// extractComponentXML returns the raw <component>...</component> markup
+// from a metainfo file's bytes, with any XML declaration and surrounding
+// whitespace stripped, so it can be embedded verbatim into a catalog
+// without a lossy unmarshal/marshal round-trip through Component. Anything
+// else that precedes <component> after the declaration, such as a
+// comment, is kept as is.
+func extractComponentXML(data []byte) string {
+	s := strings.TrimSpace(string(data))
+	if strings.HasPrefix(s, "<?xml") {
+		if idx := strings.Index(s, "?>"); idx != -1 {
+			s = strings.TrimSpace(s[idx+2:])
+		}
+	}
+	return s
+}
+
+// indentXML prefixes every non-empty line of s with indent.
+func indentXML(s, indent string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		lines[i] = indent + line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,53 @@
+package main
+
+import "encoding/xml"
+
+// Launchable describes how a component can be started by the system,
+// e.g. <launchable type="desktop-id">myapp.desktop</launchable> or
+// <launchable type="url">https://example.com/app</launchable>.
+type Launchable struct {
+	Type     string `xml:"type,attr"`
+	Contents string `xml:",chardata"`
+}
+
+// Component is the root <component/> element of a metainfo file.
+type Component struct {
+	XMLName         xml.Name                `xml:"component"`
+	Type            string                  `xml:"type,attr"`
+	ID              string                  `xml:"id"`
+	Name            TranslatableString      `xml:"name"`
+	Summary         TranslatableString      `xml:"summary"`
+	MetadataLicense string                  `xml:"metadata_license"`
+	ProjectLicense  string                  `xml:"project_license"`
+	Description     TranslatableDescription `xml:"description"`
+	Launchable      Launchable              `xml:"launchable"`
+	Screenshots     []Screenshot            `xml:"screenshots>screenshot"`
+}
+
+// Screenshot is a single <screenshot/> entry, holding one or more
+// <image/> variants (source, thumbnail) and/or <video/> sources.
+type Screenshot struct {
+	Caption     TranslatableString `xml:"caption"`
+	Images      []Image            `xml:"image"`
+	Videos      []Video            `xml:"video"`
+	Environment string             `xml:"environment,attr"`
+}
+
+// Image is a <image/> child of a <screenshot/>. type is "source" or
+// "thumbnail"; width/height are only required for thumbnails, but many
+// generators include them for the source image too.
+type Image struct {
+	Type   string `xml:"type,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+	Source string `xml:",chardata"`
+}
+
+// Video is a <video/> child of a <screenshot/>.
+type Video struct {
+	Codec     string `xml:"codec,attr"`
+	Container string `xml:"container,attr"`
+	Width     int    `xml:"width,attr"`
+	Height    int    `xml:"height,attr"`
+	Source    string `xml:",chardata"`
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DesktopEntry holds the subset of the freedesktop.org Desktop Entry
+// specification's "[Desktop Entry]" group that appstreamlint cross-checks
+// against a component's metainfo file.
+type DesktopEntry struct {
+	Type string
+	Icon string
+	Name string
+}
+
+// parseDesktopFile reads the "[Desktop Entry]" group of a .desktop file.
+// Other groups (e.g. "[Desktop Action foo]") are ignored.
+func parseDesktopFile(path string) (DesktopEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return DesktopEntry{}, err
+	}
+	defer f.Close()
+
+	var entry DesktopEntry
+	inDesktopEntryGroup := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inDesktopEntryGroup = line == "[Desktop Entry]"
+			continue
+		}
+		if !inDesktopEntryGroup {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		switch key {
+		case "Type":
+			entry.Type = value
+		case "Icon":
+			entry.Icon = value
+		case "Name":
+			entry.Name = value
+		}
+	}
+	return entry, scanner.Err()
+}
+
+// findIcon reports whether an icon named iconName exists under dir, either
+// in a hicolor icon theme layout (<dir>/hicolor/<size>/apps/<iconName>.<ext>)
+// or directly inside dir, for simpler layouts.
+func findIcon(dir, iconName string) bool {
+	if dir == "" || iconName == "" {
+		return false
+	}
+	exts := []string{".png", ".svg", ".xpm"}
+	for _, ext := range exts {
+		if _, err := os.Stat(filepath.Join(dir, iconName+ext)); err == nil {
+			return true
+		}
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "hicolor", "*", "apps", iconName+".*"))
+	return len(matches) > 0
+}
+
+// validateDesktopFile cross-checks a desktop-id launchable against the
+// .desktop file it names on disk and, when requireIcon is set, the icon
+// that file declares. This mirrors the checks appstream-generator
+// performs when building a catalog, and would have caught media players
+// and similar apps shipping a metainfo file without a matching icon.
+func validateDesktopFile(filePath string, component Component, opts ValidateOptions, requireIcon bool) []Hint {
+	if component.Launchable.Type != "desktop-id" || component.Launchable.Contents == "" {
+		return nil
+	}
+
+	desktopDir := opts.DesktopDir
+	if desktopDir == "" {
+		desktopDir = filepath.Dir(filePath)
+	}
+	desktopPath := filepath.Join(desktopDir, component.Launchable.Contents)
+
+	entry, err := parseDesktopFile(desktopPath)
+	if err != nil {
+		return []Hint{warningHint("missing-launchable-desktop-file",
+			fmt.Sprintf("launchable %q not found under %s: %s", component.Launchable.Contents, desktopDir, err))}
+	}
+
+	if !requireIcon || entry.Type != "Application" {
+		return nil
+	}
+
+	if entry.Icon == "" {
+		return []Hint{warningHint("gui-app-without-icon",
+			fmt.Sprintf("%s has Type=Application but no Icon= key", desktopPath))}
+	}
+
+	if filepath.IsAbs(entry.Icon) {
+		if _, err := os.Stat(entry.Icon); err != nil {
+			return []Hint{warningHint("gui-app-without-icon",
+				fmt.Sprintf("icon %q declared in %s does not exist", entry.Icon, desktopPath))}
+		}
+		return nil
+	}
+
+	iconsDir := opts.IconsDir
+	if iconsDir == "" {
+		iconsDir = filepath.Dir(filePath)
+	}
+	if !findIcon(iconsDir, entry.Icon) {
+		return []Hint{warningHint("gui-app-without-icon",
+			fmt.Sprintf("icon %q declared in %s was not found under %s/hicolor/*/apps", entry.Icon, desktopPath, iconsDir))}
+	}
+	return nil
+}
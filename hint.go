@@ -0,0 +1,65 @@
+package main
+
+// Severity classifies how serious a Hint is, following the same scale
+// used by appstream-generator: an error means the component must be
+// fixed before it can be included in a catalog, a warning should be
+// fixed but isn't fatal, info is purely informational, and pedantic is
+// only interesting to metadata perfectionists.
+type Severity string
+
+const (
+	SeverityError    Severity = "error"
+	SeverityWarning  Severity = "warning"
+	SeverityInfo     Severity = "info"
+	SeverityPedantic Severity = "pedantic"
+)
+
+// rank orders severities from most to least serious, so they can be
+// compared against a --fail-on threshold.
+func (s Severity) rank() int {
+	switch s {
+	case SeverityError:
+		return 0
+	case SeverityWarning:
+		return 1
+	case SeverityInfo:
+		return 2
+	case SeverityPedantic:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// atLeastAsSevereAs reports whether s is at least as serious as other,
+// e.g. SeverityError.atLeastAsSevereAs(SeverityWarning) is true.
+func (s Severity) atLeastAsSevereAs(other Severity) bool {
+	return s.rank() <= other.rank()
+}
+
+// Hint is a single validation finding. It is modelled after the hint
+// catalog used by asgen (appstream-generator): a stable Tag identifies
+// the kind of issue so tooling can filter or suppress it, Severity says
+// how bad it is, and Message is the human-readable explanation. Line and
+// Column hold the offending tag's position in the source file; none of
+// the validation rules record a position, so both are zero and are left
+// out of the JSON, YAML and SARIF output.
+type Hint struct {
+	Tag      string   `json:"tag" yaml:"tag"`
+	Severity Severity `json:"severity" yaml:"severity"`
+	Message  string   `json:"message" yaml:"message"`
+	Line     int      `json:"line,omitempty" yaml:"line,omitempty"`
+	Column   int      `json:"column,omitempty" yaml:"column,omitempty"`
+}
+
+func errorHint(tag, message string) Hint {
+	return Hint{Tag: tag, Severity: SeverityError, Message: message}
+}
+
+func warningHint(tag, message string) Hint {
+	return Hint{Tag: tag, Severity: SeverityWarning, Message: message}
+}
+
+func infoHint(tag, message string) Hint {
+	return Hint{Tag: tag, Severity: SeverityInfo, Message: message}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// maxComponentIDLength is a practical upper bound on <id/> length; the
+// AppStream spec doesn't mandate one, but tooling such as appstreamcli
+// rejects absurdly long IDs.
+const maxComponentIDLength = 255
+
+// idSegmentPattern matches one reverse-DNS segment of <id/>: a letter
+// followed by any number of letters, digits, underscores or hyphens.
+var idSegmentPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// recognizedTLDs is a representative sample of real top-level domains.
+// An <id/> whose leading segment is one of these, but isn't also on the
+// wellKnownTLDNamespaces allowlist (e.g. "net.example.App"), gets an
+// informational hint. Only the leading segment is checked, so an id in
+// forward domain order such as "example.com.App" is not flagged.
+var recognizedTLDs = map[string]bool{
+	"com": true, "net": true, "org": true, "edu": true, "gov": true,
+	"mil": true, "info": true, "biz": true, "co": true, "me": true,
+	"us": true, "ca": true, "de": true, "uk": true, "nl": true,
+	"fr": true, "jp": true, "ru": true, "io": true, "dev": true,
+}
+
+// wellKnownTLDNamespaces are reverse-DNS first segments that are the
+// correct, widely-used namespace prefix for a great many legitimate IDs
+// (e.g. io.github.foo, org.gnome.Foo, com.example.Foo). They are
+// exempted from the recognized-TLD leading-segment check below.
+var wellKnownTLDNamespaces = map[string]bool{
+	"io": true, "org": true, "com": true,
+}
+
+// validateStrictID validates <id/> as a reverse-DNS string, and checks
+// that a desktop-id launchable and the on-disk filename are coherent
+// with it. This is only run under --strict-id: real-world distros
+// differ on whether they enforce reverse-DNS IDs at all (see the
+// asv-cid-desktopapp-is-not-rdns hint in the Alpine/xpra report), so it
+// is opt-in rather than a default error.
+func validateStrictID(filePath string, component Component) []Hint {
+	id := component.ID
+	if id == "" {
+		return nil // already reported by validateCommonFields
+	}
+
+	var hints []Hint
+
+	if len(id) > maxComponentIDLength {
+		hints = append(hints, errorHint("asv-cid-too-long",
+			fmt.Sprintf("id %q is %d characters long, longer than the %d character limit", id, len(id), maxComponentIDLength)))
+	}
+
+	if strings.Contains(id, "..") {
+		hints = append(hints, errorHint("asv-cid-has-empty-segment", fmt.Sprintf("id %q contains consecutive dots", id)))
+	}
+
+	segments := strings.Split(id, ".")
+	if len(segments) < 2 {
+		hints = append(hints, errorHint("asv-cid-desktopapp-is-not-rdns",
+			fmt.Sprintf("id %q must have at least two dot-separated segments to follow the reverse-DNS scheme, e.g. org.example.MyApp", id)))
+	} else {
+		for _, seg := range segments {
+			if seg == "" {
+				continue // already reported via asv-cid-has-empty-segment
+			}
+			if !idSegmentPattern.MatchString(seg) {
+				hints = append(hints, errorHint("asv-cid-invalid-segment",
+					fmt.Sprintf("id segment %q must start with a letter and contain only letters, digits, '-' or '_'", seg)))
+			}
+		}
+		if leading := segments[0]; leading != "" && recognizedTLDs[leading] && !wellKnownTLDNamespaces[leading] {
+			// Informational rather than a warning: a generic TLD prefix
+			// is often a genuine, valid reverse-DNS namespace, just not
+			// one of the handful this tool recognizes by name.
+			hints = append(hints, infoHint("asv-cid-leading-segment-generic-tld",
+				fmt.Sprintf("id %q has leading segment %q, a generic top-level domain that isn't one of the established reverse-DNS namespaces (io, org, com); check the segments aren't in forward domain order", id, leading)))
+		}
+	}
+
+	if component.Launchable.Type == "desktop-id" && component.Launchable.Contents != "" {
+		want := id + ".desktop"
+		if component.Launchable.Contents != want {
+			hints = append(hints, warningHint("asv-cid-desktop-id-mismatch",
+				fmt.Sprintf("launchable desktop-id %q does not follow the <id/> + \".desktop\" convention, expected %q", component.Launchable.Contents, want)))
+		}
+	}
+
+	// desktop-application/desktop already get an unconditional, error-level
+	// version of this same check from desktopAppValidator, so skip it here
+	// to avoid reporting the same problem twice.
+	if component.Type != "desktop-application" && component.Type != "desktop" {
+		base := filepath.Base(filePath)
+		wantFilename := id + ".metainfo.xml"
+		if base != wantFilename && base != id+".appdata.xml" {
+			hints = append(hints, warningHint("asv-cid-filename-mismatch",
+				fmt.Sprintf("filename %q does not case-sensitively match %q", base, wantFilename)))
+		}
+	}
+
+	return hints
+}
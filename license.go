@@ -0,0 +1,32 @@
+package main
+
+// https://www.freedesktop.org/software/appstream/docs/chap-Metadata.html#tag-metadata_license
+// NOTE: The AppStream specification might allow more licenses than the ones listed below in the future. So this implementation will only inform the user
+// if the metadata license is not allowed. The user can then check the latest AppStream specification for the allowed licenses.
+var allowedLicenses = []string{
+	"FSFAP",
+	"MIT",
+	"0BSD",
+	"CC0-1.0",
+	"CC-BY-3.0",
+	"CC-BY-4.0",
+	"CC-BY-SA-3.0",
+	"CC-BY-SA-4.0",
+	"GFDL-1.1",
+	"GFDL-1.2",
+	"GFDL-1.3",
+	"BSL-1.0",
+	"FTL",
+	"FSFUL",
+}
+
+// licenseAllowed reports whether license is one of the permissive
+// licenses accepted for metadata_license.
+func licenseAllowed(license string) bool {
+	for _, allowed := range allowedLicenses {
+		if license == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Report is the result of linting a single metainfo file: every Hint
+// collected across all validation rules, instead of stopping at the
+// first failure.
+type Report struct {
+	File  string `json:"file" yaml:"file"`
+	Hints []Hint `json:"hints" yaml:"hints"`
+}
+
+// hasAtLeast reports whether the report contains a hint at least as
+// severe as threshold, e.g. for --fail-on=warning.
+func (r Report) hasAtLeast(threshold Severity) bool {
+	for _, h := range r.Hints {
+		if h.Severity.atLeastAsSevereAs(threshold) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatText renders the report the way appstreamlint has always printed
+// results: one "Error:"/"Warning:"/"Info:" line per hint.
+func formatText(r Report) string {
+	if len(r.Hints) == 0 {
+		return "Validation complete, no hints.\n"
+	}
+	var buf bytes.Buffer
+	for _, h := range r.Hints {
+		label := "Error"
+		switch h.Severity {
+		case SeverityWarning:
+			label = "Warning"
+		case SeverityInfo:
+			label = "Info"
+		case SeverityPedantic:
+			label = "Pedantic"
+		}
+		fmt.Fprintf(&buf, "%s: %s (%s)\n", label, h.Message, h.Tag)
+	}
+	return buf.String()
+}
+
+// formatJSON renders the report as a single indented JSON object.
+func formatJSON(r Report) (string, error) {
+	out, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out) + "\n", nil
+}
+
+// formatYAML renders the report as YAML. There is no YAML dependency
+// vendored into this module, so the (deliberately small) subset of YAML
+// needed for a flat hint list is emitted by hand rather than pulling in
+// a third-party library for one report format.
+func formatYAML(r Report) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "file: %s\n", yamlScalar(r.File))
+	if len(r.Hints) == 0 {
+		buf.WriteString("hints: []\n")
+		return buf.String()
+	}
+	buf.WriteString("hints:\n")
+	for _, h := range r.Hints {
+		fmt.Fprintf(&buf, "  - tag: %s\n", yamlScalar(h.Tag))
+		fmt.Fprintf(&buf, "    severity: %s\n", yamlScalar(string(h.Severity)))
+		fmt.Fprintf(&buf, "    message: %s\n", yamlScalar(h.Message))
+		if h.Line != 0 {
+			fmt.Fprintf(&buf, "    line: %d\n", h.Line)
+		}
+		if h.Column != 0 {
+			fmt.Fprintf(&buf, "    column: %d\n", h.Column)
+		}
+	}
+	return buf.String()
+}
+
+// yamlScalar quotes s as a YAML double-quoted scalar so that colons,
+// quotes and other special characters in messages don't break parsing.
+func yamlScalar(s string) string {
+	out, _ := json.Marshal(s) // JSON string syntax is a valid subset of YAML flow scalars
+	return string(out)
+}
+
+// sarifLog is a minimal SARIF v2.1.0 log, enough to let CI tools such as
+// GitHub code scanning display appstreamlint hints as annotations.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name string `json:"name"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine,omitempty"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// sarifLevel maps appstreamlint's severities onto the three levels SARIF
+// consumers understand.
+func sarifLevel(s Severity) string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+func formatSARIF(r Report) (string, error) {
+	results := make([]sarifResult, 0, len(r.Hints))
+	for _, h := range r.Hints {
+		var region *sarifRegion
+		if h.Line != 0 {
+			region = &sarifRegion{StartLine: h.Line, StartColumn: h.Column}
+		}
+		results = append(results, sarifResult{
+			RuleID: h.Tag,
+			Level:  sarifLevel(h.Severity),
+			Message: sarifMessage{
+				Text: h.Message,
+			},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: r.File},
+					Region:           region,
+				},
+			}},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "appstreamlint"}},
+			Results: results,
+		}},
+	}
+
+	out, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out) + "\n", nil
+}
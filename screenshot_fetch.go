@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+	"time"
+)
+
+// minScreenshotSourceWidth is the minimum pixel width AppStream
+// recommends for a screenshot's source image.
+const minScreenshotSourceWidth = 620
+
+const (
+	defaultFetchTimeout   = 10 * time.Second
+	defaultFetchUserAgent = "appstreamlint/1.0 (+https://github.com/probonopd/appstreamlint)"
+)
+
+// FetchOptions configures the opt-in --fetch-screenshots checks.
+type FetchOptions struct {
+	Enabled   bool
+	Timeout   time.Duration
+	UserAgent string
+}
+
+func (o FetchOptions) timeout() time.Duration {
+	if o.Timeout > 0 {
+		return o.Timeout
+	}
+	return defaultFetchTimeout
+}
+
+func (o FetchOptions) userAgent() string {
+	if o.UserAgent != "" {
+		return o.UserAgent
+	}
+	return defaultFetchUserAgent
+}
+
+// fetchScreenshots runs the network-dependent screenshot checks: it
+// downloads each <image/> and confirms its declared width/height match
+// the actual pixels, that thumbnails are strictly smaller than their
+// source, and that the source meets AppStream's minimum width and isn't
+// a wildly unusual aspect ratio. Each failure is reported under its own
+// Hint tag so users can suppress individual checks.
+func fetchScreenshots(screenshots []Screenshot, opts FetchOptions) []Hint {
+	var hints []Hint
+	client := &http.Client{Timeout: opts.timeout()}
+
+	for _, screenshot := range screenshots {
+		hints = append(hints, fetchScreenshotEntry(client, opts, screenshot)...)
+	}
+	return hints
+}
+
+// probedImage is the result of fetching one <image/>, kept around so its
+// dimensions are available regardless of where in the document it falls.
+type probedImage struct {
+	img    Image
+	width  int
+	height int
+	err    error
+}
+
+func fetchScreenshotEntry(client *http.Client, opts FetchOptions, screenshot Screenshot) []Hint {
+	probed := make([]probedImage, len(screenshot.Images))
+	for i, img := range screenshot.Images {
+		width, height, err := probeImage(client, opts, img.Source)
+		probed[i] = probedImage{img: img, width: width, height: height, err: err}
+	}
+
+	// Resolve the source image's dimensions in a first pass so that a
+	// thumbnail listed before its source in the document is still
+	// checked against it, instead of being silently skipped.
+	var sourceWidth, sourceHeight int
+	haveSource := false
+	for _, p := range probed {
+		if p.err == nil && (p.img.Type == "source" || p.img.Type == "") {
+			sourceWidth, sourceHeight = p.width, p.height
+			haveSource = true
+		}
+	}
+
+	var hints []Hint
+	for _, p := range probed {
+		if p.err != nil {
+			hints = append(hints, errorHint("metainfo-screenshot-fetch-failed",
+				fmt.Sprintf("could not fetch %q: %s", p.img.Source, p.err)))
+			continue
+		}
+
+		if (p.img.Width != 0 && p.img.Width != p.width) || (p.img.Height != 0 && p.img.Height != p.height) {
+			hints = append(hints, errorHint("metainfo-screenshot-dimension-mismatch",
+				fmt.Sprintf("%q declares %dx%d but is actually %dx%d", p.img.Source, p.img.Width, p.img.Height, p.width, p.height)))
+		}
+
+		switch {
+		case p.img.Type == "source" || p.img.Type == "":
+			if p.width < minScreenshotSourceWidth {
+				hints = append(hints, warningHint("metainfo-screenshot-source-too-small",
+					fmt.Sprintf("%q is %dpx wide, below the recommended minimum of %dpx", p.img.Source, p.width, minScreenshotSourceWidth)))
+			}
+			if isUnusualAspectRatio(p.width, p.height) {
+				hints = append(hints, warningHint("metainfo-screenshot-aspect-ratio-unusual",
+					fmt.Sprintf("%q is %dx%d, which isn't close to the conventional 16:9 aspect ratio", p.img.Source, p.width, p.height)))
+			}
+		case p.img.Type == "thumbnail" && haveSource && (p.width >= sourceWidth || p.height >= sourceHeight):
+			hints = append(hints, warningHint("metainfo-screenshot-thumbnail-not-smaller",
+				fmt.Sprintf("thumbnail %q (%dx%d) is not smaller than its source (%dx%d)", p.img.Source, p.width, p.height, sourceWidth, sourceHeight)))
+		}
+	}
+
+	return hints
+}
+
+// probeImage fetches url and decodes just enough of it to determine its
+// pixel dimensions, without reading the whole body into memory.
+func probeImage(client *http.Client, opts FetchOptions, url string) (width, height int, err error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	req.Header.Set("User-Agent", opts.userAgent())
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	cfg, _, err := image.DecodeConfig(resp.Body)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// isUnusualAspectRatio reports whether width/height is far from the
+// conventional 16:9 ratio AppStream screenshots are expected to use.
+func isUnusualAspectRatio(width, height int) bool {
+	if height == 0 {
+		return true
+	}
+	const sixteenNine = 16.0 / 9.0
+	const tolerance = 0.2
+	ratio := float64(width) / float64(height)
+	return ratio < sixteenNine-tolerance || ratio > sixteenNine+tolerance
+}
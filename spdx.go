@@ -0,0 +1,300 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// spdxLicenseIDs is a curated subset of the SPDX license list
+// (https://spdx.org/licenses/) covering the identifiers that show up
+// most often in real-world AppStream metainfo files. It is intentionally
+// not the full SPDX list; an identifier not in this list (and not a
+// "LicenseRef-" custom license) is reported rather than silently
+// accepted, so the list can grow as new licenses are seen in practice.
+var spdxLicenseIDs = map[string]bool{
+	"0BSD": true, "Apache-2.0": true, "Artistic-2.0": true,
+	"BSD-2-Clause": true, "BSD-3-Clause": true, "BSL-1.0": true,
+	"CC0-1.0": true, "CC-BY-3.0": true, "CC-BY-4.0": true,
+	"CC-BY-SA-3.0": true, "CC-BY-SA-4.0": true,
+	"EPL-1.0": true, "EPL-2.0": true,
+	"FSFAP": true, "FSFUL": true, "FTL": true,
+	"GFDL-1.1": true, "GFDL-1.2": true, "GFDL-1.3": true,
+	"GPL-2.0-only": true, "GPL-2.0-or-later": true,
+	"GPL-3.0-only": true, "GPL-3.0-or-later": true,
+	"ISC":           true,
+	"LGPL-2.1-only": true, "LGPL-2.1-or-later": true,
+	"LGPL-3.0-only": true, "LGPL-3.0-or-later": true,
+	"MIT": true, "MPL-2.0": true, "Zlib": true,
+}
+
+// spdxExceptionIDs is a curated subset of the SPDX exceptions list, used
+// after a WITH operator, e.g. "GPL-2.0-or-later WITH Classpath-exception-2.0".
+var spdxExceptionIDs = map[string]bool{
+	"Classpath-exception-2.0": true,
+	"GCC-exception-3.1":       true,
+	"LLVM-exception":          true,
+	"OpenSSL-exception":       true,
+	"Font-exception-2.0":      true,
+}
+
+// spdxExpr is a node of a parsed SPDX license expression.
+type spdxExpr interface {
+	isSpdxExpr()
+}
+
+// spdxLicenseRef is a leaf license identifier, optionally "or later"
+// (the "+" suffix), e.g. "GPL-2.0-only+".
+type spdxLicenseRef struct {
+	id      string
+	orLater bool
+}
+
+// spdxWithExpr is "<license> WITH <exception>".
+type spdxWithExpr struct {
+	license   spdxExpr
+	exception string
+}
+
+// spdxBinExpr is "<left> AND <right>" or "<left> OR <right>".
+type spdxBinExpr struct {
+	op    string // "AND" or "OR"
+	left  spdxExpr
+	right spdxExpr
+}
+
+func (spdxLicenseRef) isSpdxExpr() {}
+func (spdxWithExpr) isSpdxExpr()   {}
+func (spdxBinExpr) isSpdxExpr()    {}
+
+// spdxParser is a small recursive-descent parser for the subset of the
+// SPDX license expression grammar AppStream metainfo files use:
+// identifiers, AND, OR, WITH, parentheses and the "+" suffix.
+type spdxParser struct {
+	tokens []string
+	pos    int
+}
+
+// parseSPDXExpression parses expr as an SPDX license expression and
+// returns its AST, or an error describing where parsing failed.
+func parseSPDXExpression(expr string) (spdxExpr, error) {
+	tokens := tokenizeSPDX(expr)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty license expression")
+	}
+	p := &spdxParser{tokens: tokens}
+	result, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("unexpected token %q at position %d", p.tokens[p.pos], p.pos+1)
+	}
+	return result, nil
+}
+
+// tokenizeSPDX splits expr into identifiers, parentheses and operators.
+// Parentheses are always their own token even when not surrounded by
+// whitespace, e.g. "(MIT)".
+func tokenizeSPDX(expr string) []string {
+	var tokens []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range expr {
+		switch {
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t' || r == '\n':
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+func (p *spdxParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *spdxParser) next() string {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+// parseOr handles the lowest-precedence operator, OR.
+func (p *spdxParser) parseOr() (spdxExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "OR" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = spdxBinExpr{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseAnd handles AND, which binds tighter than OR but looser than WITH.
+func (p *spdxParser) parseAnd() (spdxExpr, error) {
+	left, err := p.parseWith()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "AND" {
+		p.next()
+		right, err := p.parseWith()
+		if err != nil {
+			return nil, err
+		}
+		left = spdxBinExpr{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseWith handles the highest-precedence operator, WITH, which always
+// attaches an exception identifier directly to a license reference.
+func (p *spdxParser) parseWith() (spdxExpr, error) {
+	license, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() == "WITH" {
+		p.next()
+		exception := p.next()
+		if exception == "" {
+			return nil, fmt.Errorf("expected exception identifier after WITH")
+		}
+		return spdxWithExpr{license: license, exception: exception}, nil
+	}
+	return license, nil
+}
+
+func (p *spdxParser) parsePrimary() (spdxExpr, error) {
+	tok := p.next()
+	switch tok {
+	case "":
+		return nil, fmt.Errorf("unexpected end of license expression")
+	case "(":
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("expected closing parenthesis")
+		}
+		return inner, nil
+	case "AND", "OR", "WITH", ")":
+		return nil, fmt.Errorf("unexpected token %q", tok)
+	default:
+		orLater := strings.HasSuffix(tok, "+")
+		id := strings.TrimSuffix(tok, "+")
+		return spdxLicenseRef{id: id, orLater: orLater}, nil
+	}
+}
+
+// knownSPDXIdentifier reports whether id is a recognised SPDX license
+// identifier, or a custom "LicenseRef-" identifier, which the SPDX spec
+// permits for licenses not (yet) listed by SPDX.
+func knownSPDXIdentifier(id string) bool {
+	return spdxLicenseIDs[id] || strings.HasPrefix(id, "LicenseRef-")
+}
+
+// validateSPDXIdentifiers walks expr and returns an error naming the
+// first unrecognised license or exception identifier found.
+func validateSPDXIdentifiers(expr spdxExpr) error {
+	switch e := expr.(type) {
+	case spdxLicenseRef:
+		if !knownSPDXIdentifier(e.id) {
+			return fmt.Errorf("unknown SPDX license identifier %q", e.id)
+		}
+	case spdxWithExpr:
+		if err := validateSPDXIdentifiers(e.license); err != nil {
+			return err
+		}
+		if !spdxExceptionIDs[e.exception] {
+			return fmt.Errorf("unknown SPDX exception identifier %q", e.exception)
+		}
+	case spdxBinExpr:
+		if err := validateSPDXIdentifiers(e.left); err != nil {
+			return err
+		}
+		return validateSPDXIdentifiers(e.right)
+	}
+	return nil
+}
+
+// isPermissiveExpr reports whether every leaf license in expr is on the
+// permissive allow-list used for metadata_license. A compound expression
+// built with AND or OR is only considered permissive when all of its
+// branches are, since metadata_license is meant to always be freely
+// redistributable regardless of which branch a consumer takes.
+func isPermissiveExpr(expr spdxExpr) bool {
+	switch e := expr.(type) {
+	case spdxLicenseRef:
+		return licenseAllowed(e.id)
+	case spdxWithExpr:
+		return isPermissiveExpr(e.license)
+	case spdxBinExpr:
+		return isPermissiveExpr(e.left) && isPermissiveExpr(e.right)
+	default:
+		return false
+	}
+}
+
+// validateMetadataLicense parses and checks the metadata_license tag,
+// which must be a valid SPDX expression where every leaf license is
+// permissive enough to redistribute as catalog metadata.
+func validateMetadataLicense(license string) []Hint {
+	if license == "" {
+		return nil
+	}
+	expr, err := parseSPDXExpression(license)
+	if err != nil {
+		return []Hint{errorHint("metainfo-license-invalid",
+			fmt.Sprintf("metadata_license %q is not a valid SPDX expression: %s", license, err))}
+	}
+	if err := validateSPDXIdentifiers(expr); err != nil {
+		return []Hint{errorHint("metainfo-license-invalid",
+			fmt.Sprintf("metadata_license %q: %s", license, err))}
+	}
+	if !isPermissiveExpr(expr) {
+		return []Hint{warningHint("metainfo-license-invalid",
+			fmt.Sprintf("metadata_license %q contains a license that isn't on the permissive allow-list %v", license, allowedLicenses))}
+	}
+	return nil
+}
+
+// validateProjectLicense parses and checks the project_license tag. Any
+// valid SPDX expression is accepted; unlike metadata_license there is no
+// permissiveness requirement since the project itself may be under any
+// license.
+func validateProjectLicense(license string) []Hint {
+	if license == "" {
+		return nil
+	}
+	expr, err := parseSPDXExpression(license)
+	if err != nil {
+		return []Hint{errorHint("metainfo-project-license-invalid",
+			fmt.Sprintf("project_license %q is not a valid SPDX expression: %s", license, err))}
+	}
+	if err := validateSPDXIdentifiers(expr); err != nil {
+		return []Hint{errorHint("metainfo-project-license-invalid",
+			fmt.Sprintf("project_license %q: %s", license, err))}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// TranslatableString is a field that may appear once without an
+// xml:lang attribute (the default, untranslated value) and again for
+// each additional locale, e.g.
+// <name>Example</name><name xml:lang="fr">Exemple</name>.
+type TranslatableString struct {
+	Default      string
+	Translations map[string]string
+}
+
+// UnmarshalXML implements xml.Unmarshaler. encoding/xml invokes it once
+// per matching element, so repeated <name>/<name xml:lang="..."/> tags
+// accumulate into the same TranslatableString as the document is parsed.
+func (t *TranslatableString) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var content string
+	if err := d.DecodeElement(&content, &start); err != nil {
+		return err
+	}
+
+	lang := xmlLangAttr(start)
+	if lang == "" {
+		t.Default = content
+		return nil
+	}
+	if t.Translations == nil {
+		t.Translations = map[string]string{}
+	}
+	t.Translations[lang] = content
+	return nil
+}
+
+// DescriptionBlock is one child of a <description/> tag: a paragraph
+// (Tag == "p", using Text) or a list (Tag == "ul"/"ol", using Items).
+// Any other Tag is a disallowed tag that validation should flag.
+type DescriptionBlock struct {
+	Tag   string
+	Text  string
+	Items []string
+}
+
+// DescriptionContent is the structured content of one <description/>,
+// or one of its translations: a sequence of blocks in document order.
+type DescriptionContent struct {
+	Blocks []DescriptionBlock
+}
+
+// TranslatableDescription captures a <description/> tag's default
+// (untranslated) structured content plus any xml:lang translations.
+type TranslatableDescription struct {
+	Default      DescriptionContent
+	Translations map[string]DescriptionContent
+}
+
+// UnmarshalXML implements xml.Unmarshaler, walking the <description/>
+// tag's children by hand so that <p>/<ul>/<ol>/<li> structure (and any
+// disallowed tag) is preserved, which a plain chardata string would lose.
+//
+// Metainfo files translate a description by putting xml:lang on the
+// individual <p>/<li> children rather than on <description/> itself (that
+// form is only used by the merged catalog format), so each block is routed
+// to Default or Translations by its own xml:lang, not the parent's.
+func (t *TranslatableDescription) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	descLang := xmlLangAttr(start)
+
+	var order []string
+	contents := map[string]*DescriptionContent{}
+	contentFor := func(lang string) *DescriptionContent {
+		c, ok := contents[lang]
+		if !ok {
+			c = &DescriptionContent{}
+			contents[lang] = c
+			order = append(order, lang)
+		}
+		return c
+	}
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			blocks, err := decodeDescriptionBlock(d, el)
+			if err != nil {
+				return err
+			}
+			for _, b := range blocks {
+				lang := b.lang
+				if lang == "" {
+					lang = descLang
+				}
+				c := contentFor(lang)
+				c.Blocks = append(c.Blocks, b.block)
+			}
+		case xml.EndElement:
+			if el.Name == start.Name {
+				for _, lang := range order {
+					if lang == "" {
+						t.Default = *contents[lang]
+						continue
+					}
+					if t.Translations == nil {
+						t.Translations = map[string]DescriptionContent{}
+					}
+					t.Translations[lang] = *contents[lang]
+				}
+				return nil
+			}
+		}
+	}
+}
+
+// langBlock is one decoded child of <description/> together with the
+// xml:lang it was written in ("" for the default, untranslated content).
+type langBlock struct {
+	lang  string
+	block DescriptionBlock
+}
+
+// decodeDescriptionBlock decodes one child element of <description/>.
+// Unrecognised tags are still decoded (as their raw text) rather than
+// rejected outright here, so that validateDescription can report them as
+// a proper hint instead of a hard parse failure.
+func decodeDescriptionBlock(d *xml.Decoder, start xml.StartElement) ([]langBlock, error) {
+	switch start.Name.Local {
+	case "p":
+		var text string
+		if err := d.DecodeElement(&text, &start); err != nil {
+			return nil, err
+		}
+		return []langBlock{{lang: xmlLangAttr(start), block: DescriptionBlock{Tag: "p", Text: strings.TrimSpace(text)}}}, nil
+	case "ul", "ol":
+		return decodeDescriptionList(d, start)
+	default:
+		var text string
+		if err := d.DecodeElement(&text, &start); err != nil {
+			return nil, err
+		}
+		return []langBlock{{lang: xmlLangAttr(start), block: DescriptionBlock{Tag: start.Name.Local, Text: strings.TrimSpace(text)}}}, nil
+	}
+}
+
+// decodeDescriptionList decodes a <ul/ol>'s <li> children, grouping them
+// by each <li>'s own xml:lang so a translated list becomes its own block
+// containing only that language's items, rather than being merged into
+// the default list. Languages are emitted in the order first seen.
+func decodeDescriptionList(d *xml.Decoder, start xml.StartElement) ([]langBlock, error) {
+	var order []string
+	items := map[string][]string{}
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			if el.Name.Local != "li" {
+				if err := d.Skip(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			lang := xmlLangAttr(el)
+			var text string
+			if err := d.DecodeElement(&text, &el); err != nil {
+				return nil, err
+			}
+			if _, ok := items[lang]; !ok {
+				order = append(order, lang)
+			}
+			items[lang] = append(items[lang], text)
+		case xml.EndElement:
+			if el.Name == start.Name {
+				blocks := make([]langBlock, 0, len(order))
+				for _, lang := range order {
+					blocks = append(blocks, langBlock{lang: lang, block: DescriptionBlock{Tag: start.Name.Local, Items: items[lang]}})
+				}
+				return blocks, nil
+			}
+		}
+	}
+}
+
+// xmlNamespace is the namespace URI encoding/xml reports for the "xml:"
+// prefix (e.g. xml:lang), per the XML spec; it does not report the
+// literal prefix "xml" as the namespace.
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// xmlLangAttr returns the xml:lang attribute of start, or "" if absent.
+func xmlLangAttr(start xml.StartElement) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "lang" && (attr.Name.Space == "xml" || attr.Name.Space == xmlNamespace) {
+			return attr.Value
+		}
+	}
+	return ""
+}
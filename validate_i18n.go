@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// bcp47Pattern is a loose approximation of BCP 47 language tags: a 2-3
+// letter primary language subtag, optionally followed by script/region/
+// variant subtags separated by hyphens (e.g. "fr", "pt-BR", "zh-Hans").
+// It is intentionally permissive - the goal is to catch obvious mistakes
+// like "french", not to fully validate the IANA subtag registry.
+var bcp47Pattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{1,8})*$`)
+
+// validateTranslatableString checks a TranslatableString field shared by
+// every component type: name and summary. Screenshot captions are not
+// passed through it. field is
+// the human-readable field name used in messages, and tagPrefix names
+// the Hint tag family to use, e.g. "metainfo-name".
+func validateTranslatableString(field string, ts TranslatableString, tagPrefix string) []Hint {
+	var hints []Hint
+
+	if ts.Default == "" {
+		hints = append(hints, errorHint(tagPrefix+"-missing", fmt.Sprintf("%s must not be empty", field)))
+	}
+
+	for _, lang := range sortedLangs(ts.Translations) {
+		value := ts.Translations[lang]
+		if !bcp47Pattern.MatchString(lang) {
+			hints = append(hints, warningHint(tagPrefix+"-lang-invalid",
+				fmt.Sprintf("%s translation locale %q is not a valid BCP 47 language tag", field, lang)))
+		}
+		if ts.Default != "" && isDramaticLengthMismatch(len(value), len(ts.Default)) {
+			hints = append(hints, warningHint(tagPrefix+"-length-mismatch",
+				fmt.Sprintf("%s translation %q is %d characters long but the default is %d; translations shouldn't be dramatically longer or shorter", field, lang, len(value), len(ts.Default))))
+		}
+	}
+
+	return hints
+}
+
+// isDramaticLengthMismatch reports whether translated is more than 3x
+// longer, or less than a third of, the default's length.
+func isDramaticLengthMismatch(translated, def int) bool {
+	if def == 0 {
+		return false
+	}
+	ratio := float64(translated) / float64(def)
+	return ratio > 3 || ratio < (1.0/3.0)
+}
+
+// allowedDescriptionTags are the only child elements the AppStream spec
+// permits directly inside <description/>.
+var allowedDescriptionTags = map[string]bool{"p": true, "ul": true, "ol": true}
+
+// validateDescription checks the default description and every
+// translation for disallowed tags, and that a default exists at all.
+func validateDescription(desc TranslatableDescription) []Hint {
+	var hints []Hint
+
+	if len(desc.Default.Blocks) == 0 {
+		hints = append(hints, errorHint("metainfo-description-missing", "description must not be empty"))
+	}
+
+	hints = append(hints, validateDescriptionContent(desc.Default, "")...)
+	for _, lang := range sortedDescriptionLangs(desc.Translations) {
+		hints = append(hints, validateDescriptionContent(desc.Translations[lang], lang)...)
+	}
+
+	return hints
+}
+
+func validateDescriptionContent(content DescriptionContent, lang string) []Hint {
+	var hints []Hint
+	for _, block := range content.Blocks {
+		if !allowedDescriptionTags[block.Tag] {
+			hints = append(hints, errorHint("metainfo-description-has-disallowed-tag",
+				fmt.Sprintf("<description>%s contains disallowed tag <%s>; only <p>, <ul> and <ol> are allowed", langSuffix(lang), block.Tag)))
+		}
+	}
+	return hints
+}
+
+// langSuffix renders " translation \"fr\"" for use in a message, or ""
+// for the default (untranslated) content.
+func langSuffix(lang string) string {
+	if lang == "" {
+		return ""
+	}
+	return fmt.Sprintf(" translation %q", lang)
+}
+
+// sortedLangs returns translations' locale keys in sorted order, so
+// hints about multiple translations come out in a stable, reproducible
+// order instead of following Go's randomized map iteration.
+func sortedLangs(translations map[string]string) []string {
+	langs := make([]string, 0, len(translations))
+	for lang := range translations {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// sortedDescriptionLangs is sortedLangs for a TranslatableDescription's
+// Translations map.
+func sortedDescriptionLangs(translations map[string]DescriptionContent) []string {
+	langs := make([]string, 0, len(translations))
+	for lang := range translations {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
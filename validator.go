@@ -0,0 +1,218 @@
+package main
+
+import "fmt"
+
+// ValidateOptions carries the optional, user-supplied context a
+// Validator may need beyond the parsed Component itself, such as where
+// to look for the .desktop file and icons a launchable refers to.
+type ValidateOptions struct {
+	// DesktopDir is the directory to search for the .desktop file named
+	// by a desktop-id launchable. If empty, the directory containing
+	// the metainfo file itself is used.
+	DesktopDir string
+	// IconsDir is the directory to search for a hicolor-themed icon
+	// (<IconsDir>/hicolor/<size>/apps/<name>.<ext>). If empty, the
+	// directory containing the metainfo file itself is used.
+	IconsDir string
+	// StrictID enables reverse-DNS validation of <id/> and coherence
+	// checks between <id/>, a desktop-id launchable and the filename.
+	StrictID bool
+	// Fetch configures the opt-in --fetch-screenshots checks.
+	Fetch FetchOptions
+}
+
+// Validator performs the validation rules specific to one AppStream
+// component type (the "type" attribute of <component/>). Each component
+// type has its own required/forbidden tags and its own launchable
+// semantics, so validation is dispatched to the Validator registered for
+// component.Type rather than handled by one monolithic function.
+//
+// Validate collects every Hint it finds instead of stopping at the first
+// one, so a single run can report everything wrong with a file at once.
+type Validator interface {
+	Validate(filePath string, component Component, opts ValidateOptions) []Hint
+}
+
+// validators maps an AppStream component type to the Validator
+// responsible for it. It is populated by registerValidator in each
+// validators_*.go file's init function.
+var validators = map[string]Validator{}
+
+// registerValidator associates a Validator with one or more component
+// types, e.g. "desktop-application" and its legacy alias "desktop".
+func registerValidator(v Validator, componentTypes ...string) {
+	for _, t := range componentTypes {
+		validators[t] = v
+	}
+}
+
+// validateCommonFields checks the tags required on every component type,
+// regardless of Type: id, name, summary, description and the two
+// license tags.
+func validateCommonFields(component Component) []Hint {
+	var hints []Hint
+
+	requiredFields := []struct {
+		tag, field, value string
+	}{
+		{"metainfo-id-missing", "ID", component.ID},
+		{"metainfo-metadata-license-missing", "MetadataLicense", component.MetadataLicense},
+		{"metainfo-project-license-missing", "ProjectLicense", component.ProjectLicense},
+	}
+	for _, f := range requiredFields {
+		if f.value == "" {
+			hints = append(hints, errorHint(f.tag, fmt.Sprintf("%s must not be empty", f.field)))
+		}
+	}
+
+	hints = append(hints, validateTranslatableString("name", component.Name, "metainfo-name")...)
+	hints = append(hints, validateTranslatableString("summary", component.Summary, "metainfo-summary")...)
+
+	if component.Name.Default != "" && len(component.Name.Default) < 2 {
+		hints = append(hints, errorHint("metainfo-name-too-short", "name must be at least 2 characters long"))
+	}
+
+	if component.Summary.Default != "" && len(component.Summary.Default) < 10 {
+		hints = append(hints, errorHint("metainfo-summary-too-short", "summary must be at least 10 characters long"))
+	}
+
+	hints = append(hints, validateDescription(component.Description)...)
+	hints = append(hints, validateMetadataLicense(component.MetadataLicense)...)
+	hints = append(hints, validateProjectLicense(component.ProjectLicense)...)
+
+	return hints
+}
+
+// requireDesktopIDLaunchable validates the <launchable/> tag for
+// component types that are started via a .desktop file, such as
+// desktop-application and console-application. missingTag lets callers
+// report a type-specific tag when the launchable is absent entirely,
+// e.g. "desktop-app-launchable-missing" for desktop-application.
+func requireDesktopIDLaunchable(component Component, missingTag string) []Hint {
+	if component.Launchable.Type == "" || component.Launchable.Contents == "" {
+		return []Hint{errorHint(missingTag, "launchable must not be empty")}
+	}
+	if component.Launchable.Type != "desktop-id" {
+		return []Hint{errorHint("metainfo-launchable-type-invalid",
+			fmt.Sprintf("launchable type must be 'desktop-id', got %q", component.Launchable.Type))}
+	}
+	return nil
+}
+
+// validateStrictIDIfEnabled runs the --strict-id checks when opts.StrictID
+// is set, and is a no-op otherwise. Every Validator calls this so the
+// mode applies uniformly across component types.
+func validateStrictIDIfEnabled(filePath string, component Component, opts ValidateOptions) []Hint {
+	if !opts.StrictID {
+		return nil
+	}
+	return validateStrictID(filePath, component)
+}
+
+// forbidLaunchable rejects a <launchable/> tag on component types that
+// are not started by the user directly, such as addon or font.
+func forbidLaunchable(component Component) []Hint {
+	if component.Launchable.Type != "" || component.Launchable.Contents != "" {
+		return []Hint{errorHint("metainfo-launchable-not-allowed",
+			fmt.Sprintf("launchable is not allowed for component type %q", component.Type))}
+	}
+	return nil
+}
+
+// allowedVideoCodecs and allowedVideoContainers are the combinations the
+// AppStream spec recommends for <video/> sources.
+var allowedVideoCodecs = map[string]bool{"av1": true, "vp9": true}
+var allowedVideoContainers = map[string]bool{"webm": true, "matroska": true}
+
+// validateScreenshots applies the shared <screenshots/> rules used by
+// every component type that may carry screenshots. These are the static
+// checks that don't require fetching anything; see fetchScreenshots for
+// the opt-in network-dependent checks run under --fetch-screenshots.
+func validateScreenshots(component Component) []Hint {
+	if len(component.Screenshots) == 0 {
+		return []Hint{warningHint("metainfo-no-screenshots", "no screenshots tag found")}
+	}
+
+	var hints []Hint
+	for _, screenshot := range component.Screenshots {
+		hints = append(hints, validateScreenshotEntry(screenshot)...)
+	}
+	return hints
+}
+
+// validateScreenshotEntry validates a single <screenshot/>.
+func validateScreenshotEntry(screenshot Screenshot) []Hint {
+	var hints []Hint
+	hasSource := false
+
+	for _, image := range screenshot.Images {
+		if image.Type != "source" && image.Type != "thumbnail" && image.Type != "" {
+			hints = append(hints, errorHint("metainfo-screenshot-image-type-invalid",
+				fmt.Sprintf("image type must be 'source' or 'thumbnail', got %q", image.Type)))
+			continue
+		}
+		if image.Type == "source" || image.Type == "" {
+			hasSource = true
+		}
+		hints = append(hints, validateScreenshotImageSource(image)...)
+	}
+
+	for _, video := range screenshot.Videos {
+		hasSource = true
+		hints = append(hints, validateScreenshotVideo(video)...)
+	}
+
+	if !hasSource {
+		hints = append(hints, errorHint("metainfo-screenshot-no-source", `screenshot must have at least one type="source" image or a video`))
+	}
+
+	return hints
+}
+
+// validateScreenshotImageSource checks a single <image/>'s source URL.
+// NOTE: For simplicity, this only checks that the source starts with
+// http:// or https:// and ends with a valid image extension, rather than
+// fully validating it as an RFC 3986 URL.
+func validateScreenshotImageSource(image Image) []Hint {
+	if len(image.Source) < 8 || (image.Source[:7] != "http://" && image.Source[:8] != "https://") {
+		return []Hint{errorHint("metainfo-screenshot-image-source-invalid", "image source must start with http:// or https://")}
+	}
+
+	validExtensions := []string{".png", ".jpg", ".jpeg"} // NOTE: It is debatable whether other image extensions should be allowed
+	for _, ext := range validExtensions {
+		if len(image.Source) > len(ext) && image.Source[len(image.Source)-len(ext):] == ext {
+			return nil
+		}
+	}
+	return []Hint{errorHint("metainfo-screenshot-image-extension-invalid",
+		fmt.Sprintf("image source must end with a valid image extension %v, got %q", validExtensions, image.Source))}
+}
+
+// screenshotHints runs the static screenshot checks, plus the opt-in
+// network-dependent ones from fetchScreenshots when opts.Fetch.Enabled.
+func screenshotHints(component Component, opts ValidateOptions) []Hint {
+	hints := validateScreenshots(component)
+	if opts.Fetch.Enabled {
+		hints = append(hints, fetchScreenshots(component.Screenshots, opts.Fetch)...)
+	}
+	return hints
+}
+
+// validateScreenshotVideo checks a single <video/>'s codec and container
+// attributes against the combinations the AppStream spec recommends.
+func validateScreenshotVideo(video Video) []Hint {
+	if video.Codec == "" || video.Container == "" {
+		return []Hint{errorHint("metainfo-screenshot-video-missing-attrs", "video must have both codec and container attributes")}
+	}
+
+	var hints []Hint
+	if !allowedVideoCodecs[video.Codec] {
+		hints = append(hints, errorHint("metainfo-screenshot-video-codec-invalid",
+			fmt.Sprintf("video codec %q is not allowed, must be one of av1, vp9", video.Codec)))
+	}
+	if !allowedVideoContainers[video.Container] {
+		hints = append(hints, errorHint("metainfo-screenshot-video-container-invalid",
+			fmt.Sprintf("video container %q is not allowed, must be one of webm, matroska", video.Container)))
+	}
+	return hints
+}
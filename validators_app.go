@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+func init() {
+	registerValidator(desktopAppValidator{}, "desktop-application", "desktop")
+	registerValidator(consoleAppValidator{}, "console-application")
+	registerValidator(webAppValidator{}, "web-application")
+}
+
+// desktopAppValidator validates components of type "desktop-application".
+// "desktop" is the pre-2016 identifier for the same component type and is
+// still accepted here, because the AppStream format should always be
+// backwards compatible.
+type desktopAppValidator struct{}
+
+func (desktopAppValidator) Validate(filePath string, component Component, opts ValidateOptions) []Hint {
+	var hints []Hint
+
+	base := filepath.Base(filePath)
+	if base != component.ID+".appdata.xml" && base != component.ID+".metainfo.xml" {
+		hints = append(hints, errorHint("metainfo-filename-mismatch",
+			fmt.Sprintf("filename must be the same as the ID with .metainfo.xml extension, got %q, want %q", base, component.ID+".metainfo.xml")))
+	}
+
+	hints = append(hints, validateCommonFields(component)...)
+
+	// For desktop applications, the <id/> tag value is commonly expected to follow the
+	// reverse-DNS scheme (e.g. org.gnome.gedit, org.kde.dolphin, etc.).
+	// NOTE: Reverse-DNS is only enforced under --strict-id, since we think requiring it
+	// unconditionally complicates things for new developers without a domain.
+	hints = append(hints, validateStrictIDIfEnabled(filePath, component, opts)...)
+
+	hints = append(hints, requireDesktopIDLaunchable(component, "desktop-app-launchable-missing")...)
+	hints = append(hints, validateDesktopFile(filePath, component, opts, true)...)
+	hints = append(hints, screenshotHints(component, opts)...)
+
+	return hints
+}
+
+// consoleAppValidator validates components of type "console-application".
+// These are launched the same way as desktop applications (via a
+// .desktop file with Terminal=true) but are not required to ship a GUI,
+// so screenshots are optional and no icon is required.
+type consoleAppValidator struct{}
+
+func (consoleAppValidator) Validate(filePath string, component Component, opts ValidateOptions) []Hint {
+	var hints []Hint
+	hints = append(hints, validateCommonFields(component)...)
+	hints = append(hints, validateStrictIDIfEnabled(filePath, component, opts)...)
+	hints = append(hints, requireDesktopIDLaunchable(component, "metainfo-launchable-missing")...)
+	hints = append(hints, validateDesktopFile(filePath, component, opts, false)...)
+	if len(component.Screenshots) > 0 {
+		hints = append(hints, screenshotHints(component, opts)...)
+	}
+	return hints
+}
+
+// webAppValidator validates components of type "web-application", which
+// are launched via a URL rather than a .desktop file.
+type webAppValidator struct{}
+
+func (webAppValidator) Validate(filePath string, component Component, opts ValidateOptions) []Hint {
+	var hints []Hint
+	hints = append(hints, validateCommonFields(component)...)
+	hints = append(hints, validateStrictIDIfEnabled(filePath, component, opts)...)
+
+	if component.Launchable.Type != "url" {
+		hints = append(hints, errorHint("metainfo-launchable-type-invalid",
+			fmt.Sprintf("launchable type must be 'url' for a web-application, got %q", component.Launchable.Type)))
+	} else if component.Launchable.Contents == "" {
+		hints = append(hints, errorHint("metainfo-launchable-missing", "launchable must not be empty"))
+	}
+
+	hints = append(hints, screenshotHints(component, opts)...)
+	return hints
+}
@@ -0,0 +1,58 @@
+package main
+
+func init() {
+	registerValidator(noLaunchableValidator{}, "addon", "font", "codec", "inputmethod", "firmware", "driver", "localization")
+	registerValidator(serviceValidator{}, "service")
+	registerValidator(runtimeValidator{}, "runtime")
+	registerValidator(genericValidator{}, "generic")
+}
+
+// noLaunchableValidator validates component types that are never started
+// directly by the user, such as addon, font, codec, inputmethod,
+// firmware, driver and localization. They share the same rule set: the
+// common fields must be present and a <launchable/> tag is forbidden.
+type noLaunchableValidator struct{}
+
+func (noLaunchableValidator) Validate(filePath string, component Component, opts ValidateOptions) []Hint {
+	var hints []Hint
+	hints = append(hints, validateCommonFields(component)...)
+	hints = append(hints, validateStrictIDIfEnabled(filePath, component, opts)...)
+	hints = append(hints, forbidLaunchable(component)...)
+	return hints
+}
+
+// serviceValidator validates components of type "service", i.e. system
+// services started by init/systemd rather than by a user.
+type serviceValidator struct{}
+
+func (serviceValidator) Validate(filePath string, component Component, opts ValidateOptions) []Hint {
+	var hints []Hint
+	hints = append(hints, validateCommonFields(component)...)
+	hints = append(hints, validateStrictIDIfEnabled(filePath, component, opts)...)
+	hints = append(hints, forbidLaunchable(component)...)
+	return hints
+}
+
+// runtimeValidator validates components of type "runtime", e.g. Flatpak
+// runtimes. A runtime is never launched on its own.
+type runtimeValidator struct{}
+
+func (runtimeValidator) Validate(filePath string, component Component, opts ValidateOptions) []Hint {
+	var hints []Hint
+	hints = append(hints, validateCommonFields(component)...)
+	hints = append(hints, validateStrictIDIfEnabled(filePath, component, opts)...)
+	hints = append(hints, forbidLaunchable(component)...)
+	return hints
+}
+
+// genericValidator validates components of type "generic", the catch-all
+// type for anything not covered by a more specific type. Only the fields
+// shared by every component type are enforced.
+type genericValidator struct{}
+
+func (genericValidator) Validate(filePath string, component Component, opts ValidateOptions) []Hint {
+	var hints []Hint
+	hints = append(hints, validateCommonFields(component)...)
+	hints = append(hints, validateStrictIDIfEnabled(filePath, component, opts)...)
+	return hints
+}